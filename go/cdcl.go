@@ -0,0 +1,468 @@
+package sss
+
+import "math"
+
+// RestartPolicy selects how a DefaultSolver decides to abandon the current
+// search branch and restart from decision level 0, keeping what it has
+// learned so far.
+type RestartPolicy int
+
+const (
+	// NoRestart never restarts.
+	NoRestart RestartPolicy = iota
+	// LubyRestart restarts following the Luby sequence, scaled by
+	// Options.LubyUnit conflicts.
+	LubyRestart
+)
+
+// Options tunes a DefaultSolver's search.
+type Options struct {
+	// Restart is the RestartPolicy to follow.
+	Restart RestartPolicy
+	// LubyUnit is the number of conflicts making up one unit of the Luby
+	// restart sequence.
+	LubyUnit int
+	// MaxLearnedClauses is the number of learned clauses above which the
+	// clause database is reduced (see reduceDB), dropping the oldest ones;
+	// clause activity plays no part in the choice.
+	MaxLearnedClauses int
+}
+
+// DefaultOptions returns the Options used by NewDefaultSolver: Luby restarts
+// every 100 conflicts (scaled by the sequence) and a learned-clause database
+// capped at 2000 clauses.
+func DefaultOptions() Options {
+	return Options{Restart: LubyRestart, LubyUnit: 100, MaxLearnedClauses: 2000}
+}
+
+// lbool is a ternary truth value: a variable is either unassigned, or
+// assigned true or false at some decision level.
+type lbool int8
+
+const (
+	lUndef lbool = iota
+	lTrue
+	lFalse
+)
+
+// cdclClause is a clause living in the clause database, watched on its first
+// two literals.
+type cdclClause struct {
+	literals []Literal
+	learnt   bool
+	activity float64
+}
+
+func varOf(l Literal) int {
+	if l < 0 {
+		return int(-l)
+	}
+	return int(l)
+}
+
+// cdclEngine is the mutable state of one CDCL search: the clause database,
+// the trail of assigned literals, and the bookkeeping needed for conflict
+// analysis, backjumping and restarts.
+type cdclEngine struct {
+	options Options
+
+	nbVars int
+	unsat  bool
+
+	clauses  []*cdclClause
+	learnts  []*cdclClause
+	watchers map[Literal][]*cdclClause
+
+	assign []lbool
+	level  []int
+	reason []*cdclClause
+
+	trail    []Literal
+	trailLim []int
+	qhead    int
+
+	activity []float64
+	varInc   float64
+	varDecay float64
+
+	conflicts          int
+	conflictsAtRestart int
+	restartCount       int
+}
+
+func newCDCLEngine(problem Problem, options Options) *cdclEngine {
+	nbVars := 0
+	for _, clause := range problem {
+		for _, literal := range clause {
+			if v := varOf(literal); v > nbVars {
+				nbVars = v
+			}
+		}
+	}
+
+	e := &cdclEngine{
+		options:  options,
+		nbVars:   nbVars,
+		watchers: make(map[Literal][]*cdclClause),
+		assign:   make([]lbool, nbVars+1),
+		level:    make([]int, nbVars+1),
+		reason:   make([]*cdclClause, nbVars+1),
+		activity: make([]float64, nbVars+1),
+		varInc:   1,
+		varDecay: 0.95,
+	}
+	for _, clause := range problem {
+		literals := make([]Literal, len(clause))
+		copy(literals, clause)
+		e.attach(&cdclClause{literals: literals})
+	}
+	return e
+}
+
+// attach adds a clause to the database, either watching its first two
+// literals, or enqueuing it immediately if it is a unit clause, or marking
+// the whole problem unsat if it is empty. A clause can be attached after
+// some variables are already assigned — a clause learnt via backjumping, or
+// blockModel's blocking clause over a prefix of variables already forced at
+// level 0 — so literals[0] and literals[1] are first relocated away from any
+// already-false literal, the way a freshly parsed clause's watches would be
+// picked under a partial assignment; propagate's persistent queue head only
+// revisits each trail position once, so it would otherwise never notice an
+// assignment made before this clause existed.
+func (e *cdclEngine) attach(c *cdclClause) {
+	switch {
+	case len(c.literals) == 0:
+		e.unsat = true
+	case len(c.literals) == 1:
+		if !e.enqueue(c.literals[0], c) {
+			e.unsat = true
+		}
+	default:
+		for _, watched := range [2]int{0, 1} {
+			if e.value(c.literals[watched]) != lFalse {
+				continue
+			}
+			for k := 2; k < len(c.literals); k++ {
+				if e.value(c.literals[k]) != lFalse {
+					c.literals[watched], c.literals[k] = c.literals[k], c.literals[watched]
+					break
+				}
+			}
+		}
+		e.watchers[c.literals[0]] = append(e.watchers[c.literals[0]], c)
+		e.watchers[c.literals[1]] = append(e.watchers[c.literals[1]], c)
+		switch {
+		case e.value(c.literals[0]) == lFalse && e.value(c.literals[1]) == lFalse:
+			e.unsat = true
+		case e.value(c.literals[0]) == lUndef && e.value(c.literals[1]) == lFalse:
+			if !e.enqueue(c.literals[0], c) {
+				e.unsat = true
+			}
+		case e.value(c.literals[0]) == lFalse && e.value(c.literals[1]) == lUndef:
+			if !e.enqueue(c.literals[1], c) {
+				e.unsat = true
+			}
+		}
+	}
+	if !c.learnt {
+		e.clauses = append(e.clauses, c)
+	} else {
+		e.learnts = append(e.learnts, c)
+	}
+}
+
+func (e *cdclEngine) value(l Literal) lbool {
+	v := e.assign[varOf(l)]
+	if v == lUndef || l > 0 {
+		return v
+	}
+	if v == lTrue {
+		return lFalse
+	}
+	return lTrue
+}
+
+func (e *cdclEngine) decisionLevel() int {
+	return len(e.trailLim)
+}
+
+func (e *cdclEngine) enqueue(l Literal, reason *cdclClause) bool {
+	switch e.value(l) {
+	case lTrue:
+		return true
+	case lFalse:
+		return false
+	}
+	v := varOf(l)
+	if l > 0 {
+		e.assign[v] = lTrue
+	} else {
+		e.assign[v] = lFalse
+	}
+	e.level[v] = e.decisionLevel()
+	e.reason[v] = reason
+	e.trail = append(e.trail, l)
+	return true
+}
+
+// propagate processes the trail starting from e.qhead, the index of the
+// first literal not yet propagated, returning the first clause it finds
+// falsified by the current assignment, or nil once it reaches a fixpoint.
+// e.qhead persists across calls (see backtrack), so each literal's watchers
+// are visited once per propagation round instead of once per call.
+func (e *cdclEngine) propagate() *cdclClause {
+	for e.qhead < len(e.trail) {
+		falsified := e.trail[e.qhead].Negate()
+		e.qhead++
+		watching := e.watchers[falsified]
+		remaining := watching[:0]
+		for i := 0; i < len(watching); i++ {
+			c := watching[i]
+			if c.literals[0] == falsified {
+				c.literals[0], c.literals[1] = c.literals[1], c.literals[0]
+			}
+			if e.value(c.literals[0]) == lTrue {
+				remaining = append(remaining, c)
+				continue
+			}
+			newWatchFound := false
+			for k := 2; k < len(c.literals); k++ {
+				if e.value(c.literals[k]) != lFalse {
+					c.literals[1], c.literals[k] = c.literals[k], c.literals[1]
+					e.watchers[c.literals[1]] = append(e.watchers[c.literals[1]], c)
+					newWatchFound = true
+					break
+				}
+			}
+			if newWatchFound {
+				continue
+			}
+			remaining = append(remaining, c)
+			if !e.enqueue(c.literals[0], c) {
+				e.watchers[falsified] = append(remaining, watching[i+1:]...)
+				return c
+			}
+		}
+		e.watchers[falsified] = remaining
+	}
+	return nil
+}
+
+// analyze walks the implication graph backwards from the conflicting clause,
+// following the first-UIP scheme, and returns the learned clause (with the
+// asserting literal at index 0) and the level to backjump to.
+func (e *cdclEngine) analyze(conflict *cdclClause) ([]Literal, int) {
+	seen := make([]bool, e.nbVars+1)
+	learnt := []Literal{0}
+	counter := 0
+	var p Literal
+	trailIdx := len(e.trail) - 1
+	reasonClause := conflict
+
+	for {
+		for _, q := range reasonClause.literals {
+			if q == p {
+				continue
+			}
+			v := varOf(q)
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			e.bumpActivity(v)
+			if e.level[v] == e.decisionLevel() {
+				counter++
+			} else if e.level[v] > 0 {
+				learnt = append(learnt, q)
+			}
+		}
+		for !seen[varOf(e.trail[trailIdx])] {
+			trailIdx--
+		}
+		p = e.trail[trailIdx]
+		trailIdx--
+		seen[varOf(p)] = false
+		counter--
+		if counter == 0 {
+			break
+		}
+		reasonClause = e.reason[varOf(p)]
+	}
+	learnt[0] = p.Negate()
+
+	backtrackLevel := 0
+	if len(learnt) > 1 {
+		maxIdx := 1
+		for i := 2; i < len(learnt); i++ {
+			if e.level[varOf(learnt[i])] > e.level[varOf(learnt[maxIdx])] {
+				maxIdx = i
+			}
+		}
+		learnt[1], learnt[maxIdx] = learnt[maxIdx], learnt[1]
+		backtrackLevel = e.level[varOf(learnt[1])]
+	}
+	return learnt, backtrackLevel
+}
+
+func (e *cdclEngine) bumpActivity(v int) {
+	e.activity[v] += e.varInc
+	if e.activity[v] > 1e100 {
+		for i := range e.activity {
+			e.activity[i] *= 1e-100
+		}
+		e.varInc *= 1e-100
+	}
+}
+
+func (e *cdclEngine) decayActivity() {
+	e.varInc /= e.varDecay
+}
+
+// backtrack undoes every assignment made at a decision level beyond level.
+func (e *cdclEngine) backtrack(level int) {
+	if e.decisionLevel() <= level {
+		return
+	}
+	from := e.trailLim[level]
+	for i := len(e.trail) - 1; i >= from; i-- {
+		e.assign[varOf(e.trail[i])] = lUndef
+	}
+	e.trail = e.trail[:from]
+	e.trailLim = e.trailLim[:level]
+	if e.qhead > len(e.trail) {
+		e.qhead = len(e.trail)
+	}
+}
+
+// pickBranchVar returns the unassigned variable with the highest VSIDS
+// activity, or 0 if every variable is already assigned.
+func (e *cdclEngine) pickBranchVar() int {
+	best, bestActivity := 0, -1.0
+	for v := 1; v <= e.nbVars; v++ {
+		if e.assign[v] != lUndef {
+			continue
+		}
+		if best == 0 || e.activity[v] > bestActivity {
+			best, bestActivity = v, e.activity[v]
+		}
+	}
+	return best
+}
+
+func luby(base int, restartNb int) int {
+	seq, size := 0, 1
+	for size < restartNb+1 {
+		seq++
+		size = 2*size + 1
+	}
+	for size-1 != restartNb {
+		size = (size - 1) / 2
+		seq--
+		restartNb %= size
+	}
+	return base * int(math.Pow(2, float64(seq)))
+}
+
+func (e *cdclEngine) shouldRestart() bool {
+	if e.options.Restart != LubyRestart {
+		return false
+	}
+	threshold := luby(e.options.LubyUnit, e.restartCount)
+	return e.conflicts-e.conflictsAtRestart >= threshold
+}
+
+// reduceDB drops up to half of the learned clauses, oldest first, skipping
+// any that is currently the reason for an assignment on the trail. Clauses
+// are picked by age only; their activity is not consulted.
+func (e *cdclEngine) reduceDB() {
+	locked := make(map[*cdclClause]bool, len(e.trail))
+	for _, l := range e.trail {
+		if r := e.reason[varOf(l)]; r != nil {
+			locked[r] = true
+		}
+	}
+
+	kept := make([]*cdclClause, 0, len(e.learnts))
+	dropped := make([]*cdclClause, 0, len(e.learnts)/2)
+	limit := len(e.learnts) / 2
+	for _, c := range e.learnts {
+		if locked[c] || len(dropped) >= limit {
+			kept = append(kept, c)
+			continue
+		}
+		dropped = append(dropped, c)
+	}
+	for _, c := range dropped {
+		e.detach(c)
+	}
+	e.learnts = kept
+}
+
+func (e *cdclEngine) detach(c *cdclClause) {
+	for _, w := range c.literals[:2] {
+		clauses := e.watchers[w]
+		for i, wc := range clauses {
+			if wc == c {
+				e.watchers[w] = append(clauses[:i], clauses[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (e *cdclEngine) currentModel() Assignment {
+	model := make(Assignment, len(e.trail))
+	copy(model, e.trail)
+	return model
+}
+
+// blockModel forbids exactly the given model from being found again, by
+// adding its negation as a clause, and resets the search to decision level 0
+// so the next call to solve explores the remaining models.
+func (e *cdclEngine) blockModel(model Assignment) {
+	e.backtrack(0)
+	blocking := make([]Literal, len(model))
+	for i, l := range model {
+		blocking[i] = l.Negate()
+	}
+	e.attach(&cdclClause{literals: blocking})
+}
+
+// solve runs CDCL search to either find a satisfying Assignment, or
+// determine that the Problem (as currently constrained) is unsatisfiable.
+func (e *cdclEngine) solve() (Assignment, bool) {
+	if e.unsat {
+		return nil, false
+	}
+	for {
+		conflict := e.propagate()
+		if conflict != nil {
+			if e.decisionLevel() == 0 {
+				e.unsat = true
+				return nil, false
+			}
+			learnt, backtrackLevel := e.analyze(conflict)
+			e.backtrack(backtrackLevel)
+			learntClause := &cdclClause{literals: learnt, learnt: true}
+			e.attach(learntClause)
+			e.conflicts++
+			e.decayActivity()
+			if e.shouldRestart() {
+				e.backtrack(0)
+				e.conflictsAtRestart = e.conflicts
+				e.restartCount++
+			}
+			if len(e.learnts) > e.options.MaxLearnedClauses {
+				e.reduceDB()
+			}
+			continue
+		}
+
+		v := e.pickBranchVar()
+		if v == 0 {
+			return e.currentModel(), true
+		}
+		e.trailLim = append(e.trailLim, len(e.trail))
+		e.enqueue(Literal(v), nil)
+	}
+}
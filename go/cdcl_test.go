@@ -0,0 +1,120 @@
+package sss
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDefaultSolver_trivial(t *testing.T) {
+	problem := Problem{{1, 2}, {-2, 3}}
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) == 0 {
+		t.Fatal("Solve() found no solutions, want at least one")
+	}
+	for _, solution := range solutions {
+		if !satisfies(problem, solution) {
+			t.Errorf("Solve() returned %v, which does not satisfy %v", solution, problem)
+		}
+	}
+}
+
+func TestDefaultSolver_unsat(t *testing.T) {
+	problem := Problem{{1}, {-1}}
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) != 0 {
+		t.Errorf("Solve() = %v, want no solutions", solutions)
+	}
+}
+
+func TestDefaultSolver_pigeonholeIsUnsat(t *testing.T) {
+	// Three pigeons, two holes: a classic unsat instance that forces several
+	// conflicts and backjumps to solve.
+	variable := func(pigeon, hole int) Literal { return Literal(pigeon*2 + hole + 1) }
+	var problem Problem
+	for pigeon := 0; pigeon < 3; pigeon++ {
+		problem = append(problem, Clause{variable(pigeon, 0), variable(pigeon, 1)})
+	}
+	for hole := 0; hole < 2; hole++ {
+		for p1 := 0; p1 < 3; p1++ {
+			for p2 := p1 + 1; p2 < 3; p2++ {
+				problem = append(problem, Clause{variable(p1, hole).Negate(), variable(p2, hole).Negate()})
+			}
+		}
+	}
+
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) != 0 {
+		t.Errorf("Solve() = %v, want no solutions", solutions)
+	}
+}
+
+func TestDefaultSolver_enumeratesAllModels(t *testing.T) {
+	// Three independent variables have exactly 2^3 models.
+	problem := Problem{{1, -1}, {2, -2}, {3, -3}}
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) != 8 {
+		t.Errorf("len(Solve()) = %d, want 8", len(solutions))
+	}
+}
+
+func TestDefaultSolver_enumeratesAllModelsAfterVariablesLockIn(t *testing.T) {
+	// Variables 1-4 admit exactly 3 satisfying combinations; once the first
+	// two are enumerated and blocked, the third becomes forced at decision
+	// level 0 for the rest of the search. A clause blocking a model built
+	// over those now-permanently-assigned variables must still be able to
+	// relocate its watches onto the free variables 5-7 instead of getting
+	// stuck watching literals that can never be falsified again.
+	problem := Problem{
+		{1, 2, 3}, {-1, 2, 4}, {-2, 3, -4}, {1, -3, 4}, {-1, -2, -3},
+		{2, 3, 4}, {-2, -3, -4}, {1, -2, 4}, {-1, 3, -4},
+		{5, 6, 7}, {-5, -6, -7},
+	}
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) != 18 {
+		t.Errorf("len(Solve()) = %d, want 18 (3 combinations of vars 1-4 * 6 of vars 5-7)", len(solutions))
+	}
+	seen := make(map[string]bool, len(solutions))
+	for _, solution := range solutions {
+		if !satisfies(problem, solution) {
+			t.Errorf("Solve() returned %v, which does not satisfy %v", solution, problem)
+		}
+		key := fmt.Sprint(solution)
+		if seen[key] {
+			t.Errorf("Solve() returned %v more than once", solution)
+		}
+		seen[key] = true
+	}
+}
+
+func TestDefaultSolver_stopsOnFirstModel(t *testing.T) {
+	// Three independent variables have 8 models; ranging should stop after one.
+	problem := Problem{{1, -1}, {2, -2}, {3, -3}}
+	count := 0
+	for range NewDefaultSolver().Solve(problem) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("ranged over %d models before stopping, want 1", count)
+	}
+}
+
+func satisfies(problem Problem, assignment Assignment) bool {
+	set := make(map[Literal]bool, len(assignment))
+	for _, literal := range assignment {
+		set[literal] = true
+	}
+	for _, clause := range problem {
+		satisfied := false
+		for _, literal := range clause {
+			if set[literal] {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
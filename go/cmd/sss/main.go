@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/super7ramp/sss"
+)
+
+func main() {
+	fmt.Println("Example: Trivial clauses")
+	fmt.Println("Input: (1 or 2) and (-2 or 3)")
+	problem := sss.Problem{{1, 2}, {-2, 3}}
+	solver := sss.NewDefaultSolver()
+	fmt.Println("Solutions:")
+	for assignment := range solver.Solve(problem) {
+		fmt.Println(assignment)
+	}
+	fmt.Println()
+
+	fmt.Println("Example: A sudoku problem")
+	fmt.Println("Input:")
+	sudoku := sss.NewSudoku([][]int{
+		{0, 2, 6, 0, 0, 0, 8, 1, 0},
+		{3, 0, 0, 7, 0, 8, 0, 0, 6},
+		{4, 0, 0, 0, 5, 0, 0, 0, 7},
+		{0, 5, 0, 1, 0, 7, 0, 9, 0},
+		{0, 0, 3, 9, 0, 5, 1, 0, 0},
+		{0, 4, 0, 3, 0, 2, 0, 5, 0},
+		{1, 0, 0, 0, 3, 0, 0, 0, 2},
+		{5, 0, 0, 2, 0, 4, 0, 0, 9},
+		{0, 3, 8, 0, 0, 0, 4, 6, 0},
+	})
+	fmt.Println(sudoku)
+	fmt.Println("Solutions:")
+	for _, solution := range sudoku.Solutions() {
+		start := time.Now()
+		fmt.Println(solution)
+		fmt.Println("Time: ", time.Since(start))
+	}
+
+	fmt.Println()
+	fmt.Println("Same sudoku, solved with DLX:")
+	for _, solution := range sudoku.SolveWithDLX() {
+		start := time.Now()
+		fmt.Println(solution)
+		fmt.Println("Time: ", time.Since(start))
+	}
+
+	fmt.Println()
+	fmt.Println("Example: A 4x4 X-sudoku puzzle")
+	fmt.Println("Input:")
+	xGivens := sss.SudokuGrid{
+		{1, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 4},
+	}
+	fmt.Println(xGivens)
+	xSudoku := sss.NewXPuzzle(4, xGivens)
+	fmt.Println("Solutions:")
+	for _, solution := range xSudoku.Solutions() {
+		fmt.Println(solution)
+	}
+}
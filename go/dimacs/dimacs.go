@@ -0,0 +1,150 @@
+// Package dimacs reads and writes SAT problems in the DIMACS CNF format used
+// by SAT competitions and benchmark suites such as SATLIB.
+package dimacs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/super7ramp/sss"
+)
+
+// Status is the satisfiability status of a Problem, as reported by a solver
+// or recorded alongside a benchmark instance.
+type Status int
+
+const (
+	// Indeterminate means the Problem has not been solved (yet).
+	Indeterminate Status = iota
+	// Satisfiable means the Problem has at least one Assignment.
+	Satisfiable
+	// Unsatisfiable means the Problem has no Assignment.
+	Unsatisfiable
+)
+
+// Problem is a sss.Problem enriched with the metadata carried by the DIMACS
+// CNF format: the declared variable count, the unit clauses singled out, and
+// a satisfiability Status.
+type Problem struct {
+	sss.Problem
+	NbVars int
+	Units  []sss.Literal
+	Status Status
+}
+
+// Parse reads a Problem from r in DIMACS CNF format.
+//
+// The format is a sequence of lines: lines starting with 'c' are comments
+// and are ignored; one header line "p cnf <nbVars> <nbClauses>" declares the
+// number of variables and clauses; the remaining lines each list the
+// literals of a clause, terminated by a trailing 0 (a clause may span
+// several lines).
+func Parse(r io.Reader) (*Problem, error) {
+	scanner := bufio.NewScanner(r)
+	var clauses []sss.Clause
+	var current sss.Clause
+	nbVars := 0
+	sawHeader := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "c"):
+			continue
+		case strings.HasPrefix(line, "p"):
+			fields := strings.Fields(line)
+			if len(fields) < 4 || fields[1] != "cnf" {
+				return nil, fmt.Errorf("dimacs: malformed header %q", line)
+			}
+			var err error
+			if nbVars, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, fmt.Errorf("dimacs: malformed header %q: %w", line, err)
+			}
+			sawHeader = true
+		default:
+			if !sawHeader {
+				return nil, fmt.Errorf("dimacs: clause before header: %q", line)
+			}
+			for _, field := range strings.Fields(line) {
+				value, err := strconv.Atoi(field)
+				if err != nil {
+					return nil, fmt.Errorf("dimacs: malformed literal %q: %w", field, err)
+				}
+				if value == 0 {
+					clauses = append(clauses, current)
+					current = nil
+					continue
+				}
+				current = append(current, sss.Literal(value))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(current) > 0 {
+		clauses = append(clauses, current)
+	}
+
+	return &Problem{
+		Problem: clauses,
+		NbVars:  nbVars,
+		Units:   unitsOf(clauses),
+		Status:  Indeterminate,
+	}, nil
+}
+
+func unitsOf(clauses []sss.Clause) []sss.Literal {
+	var units []sss.Literal
+	for _, clause := range clauses {
+		if len(clause) == 1 {
+			units = append(units, clause[0])
+		}
+	}
+	return units
+}
+
+// Write serializes p to w in DIMACS CNF format.
+func Write(w io.Writer, p *Problem) error {
+	nbVars := p.NbVars
+	if nbVars == 0 {
+		nbVars = maxVar(p.Problem)
+	}
+	if _, err := fmt.Fprintf(w, "p cnf %d %d\n", nbVars, len(p.Problem)); err != nil {
+		return err
+	}
+	for _, clause := range p.Problem {
+		for _, literal := range clause {
+			if _, err := fmt.Fprintf(w, "%d ", literal); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, "0"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func maxVar(problem sss.Problem) int {
+	max := 0
+	for _, clause := range problem {
+		for _, literal := range clause {
+			if v := abs(int(literal)); v > max {
+				max = v
+			}
+		}
+	}
+	return max
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,87 @@
+package dimacs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/super7ramp/sss"
+)
+
+func TestParse(t *testing.T) {
+	input := `c a trivial example
+p cnf 3 2
+1 2 0
+-2 3 0
+`
+	problem, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if problem.NbVars != 3 {
+		t.Errorf("NbVars = %d, want 3", problem.NbVars)
+	}
+	want := sss.Problem{{1, 2}, {-2, 3}}
+	if !clausesEqual(problem.Problem, want) {
+		t.Errorf("Problem = %v, want %v", problem.Problem, want)
+	}
+	if len(problem.Units) != 0 {
+		t.Errorf("Units = %v, want none", problem.Units)
+	}
+}
+
+func TestParse_unitClauses(t *testing.T) {
+	input := "p cnf 2 2\n1 0\n-2 0\n"
+	problem, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []sss.Literal{1, -2}
+	if len(problem.Units) != len(want) || problem.Units[0] != want[0] || problem.Units[1] != want[1] {
+		t.Errorf("Units = %v, want %v", problem.Units, want)
+	}
+}
+
+func TestWrite_roundTrip(t *testing.T) {
+	original := &Problem{Problem: sss.Problem{{1, 2}, {-2, 3}}, NbVars: 3}
+
+	var buf strings.Builder
+	if err := Write(&buf, original); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	roundTripped, err := Parse(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !clausesEqual(roundTripped.Problem, original.Problem) {
+		t.Errorf("round-tripped Problem = %v, want %v", roundTripped.Problem, original.Problem)
+	}
+}
+
+func TestParse_solvable(t *testing.T) {
+	problem, err := Parse(strings.NewReader("p cnf 3 2\n1 2 0\n-2 3 0\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	solutions := sss.SolveAll(sss.NewDefaultSolver().Solve(problem.Problem))
+	if len(solutions) == 0 {
+		t.Error("Solve() found no solutions, want at least one")
+	}
+}
+
+func clausesEqual(a, b sss.Problem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+// Package dlx implements Knuth's Algorithm X over a sparse matrix linked
+// with Dancing Links (DLX), a backtracking search for the exact cover
+// problem: selecting a set of options (rows) such that every constraint
+// (column) is covered by exactly one of them.
+package dlx
+
+// node is one cell of the sparse matrix, linked to its neighbors in both its
+// row and its column. A column header is a node too: its down/up ring lists
+// the data nodes currently in that column, and size counts how many remain.
+type node struct {
+	left, right, up, down *node
+	header                *node
+	rowID                 int
+	size                  int
+}
+
+// ExactCover is a sparse 0/1 matrix of options against constraints, built by
+// repeated calls to AddOption and solved by Solve.
+type ExactCover struct {
+	root    *node
+	columns []*node
+	nextRow int
+}
+
+// NewExactCover creates an ExactCover over nbCols constraints, numbered
+// 0..nbCols-1.
+func NewExactCover(nbCols int) *ExactCover {
+	root := &node{}
+	root.left, root.right = root, root
+
+	columns := make([]*node, nbCols)
+	last := root
+	for i := 0; i < nbCols; i++ {
+		column := &node{rowID: -1}
+		column.header = column
+		column.up, column.down = column, column
+		column.left = last
+		column.right = root
+		last.right = column
+		root.left = column
+		last = column
+		columns[i] = column
+	}
+	return &ExactCover{root: root, columns: columns}
+}
+
+// AddOption adds an option covering the given columns, and returns the
+// option's index, which is how Solve identifies it in a solution.
+func (ec *ExactCover) AddOption(columns []int) int {
+	rowID := ec.nextRow
+	ec.nextRow++
+
+	var first *node
+	for _, columnIdx := range columns {
+		column := ec.columns[columnIdx]
+		n := &node{header: column, rowID: rowID}
+		n.up = column.up
+		n.down = column
+		column.up.down = n
+		column.up = n
+		column.size++
+
+		if first == nil {
+			first = n
+			n.left, n.right = n, n
+		} else {
+			n.left = first.left
+			n.right = first
+			first.left.right = n
+			first.left = n
+		}
+	}
+	return rowID
+}
+
+// Solve returns every selection of option indices that covers each column
+// exactly once, picking at each step the column with the fewest remaining
+// candidate options.
+func (ec *ExactCover) Solve() [][]int {
+	var solutions [][]int
+	var partial []int
+
+	var search func()
+	search = func() {
+		if ec.root.right == ec.root {
+			solution := make([]int, len(partial))
+			copy(solution, partial)
+			solutions = append(solutions, solution)
+			return
+		}
+
+		column := ec.chooseColumn()
+		cover(column)
+		for row := column.down; row != column; row = row.down {
+			partial = append(partial, row.rowID)
+			for cell := row.right; cell != row; cell = cell.right {
+				cover(cell.header)
+			}
+
+			search()
+
+			for cell := row.left; cell != row; cell = cell.left {
+				uncover(cell.header)
+			}
+			partial = partial[:len(partial)-1]
+		}
+		uncover(column)
+	}
+	search()
+
+	return solutions
+}
+
+// chooseColumn returns the uncovered column with the fewest remaining
+// options, to fail as early as possible.
+func (ec *ExactCover) chooseColumn() *node {
+	best := ec.root.right
+	for column := ec.root.right; column != ec.root; column = column.right {
+		if column.size < best.size {
+			best = column
+		}
+	}
+	return best
+}
+
+// cover removes column from the header ring, and every option that also
+// covers one of its rows from their respective columns.
+func cover(column *node) {
+	column.right.left = column.left
+	column.left.right = column.right
+	for row := column.down; row != column; row = row.down {
+		for cell := row.right; cell != row; cell = cell.right {
+			cell.down.up = cell.up
+			cell.up.down = cell.down
+			cell.header.size--
+		}
+	}
+}
+
+// uncover reverses a prior cover of column.
+func uncover(column *node) {
+	for row := column.up; row != column; row = row.up {
+		for cell := row.left; cell != row; cell = cell.left {
+			cell.header.size++
+			cell.down.up = cell
+			cell.up.down = cell
+		}
+	}
+	column.right.left = column
+	column.left.right = column
+}
+
+// Solver runs Algorithm X over an ExactCover, mirroring the shape of
+// sss.DefaultSolver so callers can pick a backend by name; it carries no
+// state of its own today but is the natural place to hang future options
+// (e.g. a solution-count limit) without changing the call sites.
+type Solver struct{}
+
+// NewSolver creates a Solver.
+func NewSolver() *Solver {
+	return &Solver{}
+}
+
+// Solve returns every solution to ec, as returned by ec.Solve.
+func (s *Solver) Solve(ec *ExactCover) [][]int {
+	return ec.Solve()
+}
@@ -0,0 +1,63 @@
+package dlx
+
+import "testing"
+
+func TestExactCover_trivial(t *testing.T) {
+	// Two columns, two disjoint options each covering one column: the only
+	// exact cover picks both.
+	ec := NewExactCover(2)
+	a := ec.AddOption([]int{0})
+	b := ec.AddOption([]int{1})
+
+	solutions := ec.Solve()
+	if len(solutions) != 1 {
+		t.Fatalf("len(Solve()) = %d, want 1", len(solutions))
+	}
+	got := solutions[0]
+	if len(got) != 2 || !contains(got, a) || !contains(got, b) {
+		t.Errorf("Solve()[0] = %v, want %v", got, []int{a, b})
+	}
+}
+
+func TestExactCover_overlappingOptionsHaveOneSolutionEach(t *testing.T) {
+	// Three columns; two options each cover all of them on their own.
+	ec := NewExactCover(3)
+	first := ec.AddOption([]int{0, 1, 2})
+	second := ec.AddOption([]int{0, 1, 2})
+
+	solutions := ec.Solve()
+	if len(solutions) != 2 {
+		t.Fatalf("len(Solve()) = %d, want 2", len(solutions))
+	}
+	if !(solutions[0][0] == first || solutions[0][0] == second) {
+		t.Errorf("Solve() = %v, want options %v or %v", solutions, first, second)
+	}
+}
+
+func TestExactCover_unsatisfiable(t *testing.T) {
+	// One column and no option covers it.
+	ec := NewExactCover(1)
+	solutions := ec.Solve()
+	if len(solutions) != 0 {
+		t.Errorf("Solve() = %v, want no solutions", solutions)
+	}
+}
+
+func TestSolver_Solve(t *testing.T) {
+	ec := NewExactCover(1)
+	want := ec.AddOption([]int{0})
+
+	solutions := NewSolver().Solve(ec)
+	if len(solutions) != 1 || len(solutions[0]) != 1 || solutions[0][0] != want {
+		t.Errorf("Solve() = %v, want [[%d]]", solutions, want)
+	}
+}
+
+func contains(options []int, target int) bool {
+	for _, option := range options {
+		if option == target {
+			return true
+		}
+	}
+	return false
+}
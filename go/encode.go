@@ -0,0 +1,254 @@
+package sss
+
+// atMostOneLogThreshold is the literal count above which AtMost switches its
+// k=1 case from the quadratic pairwise encoding to Encoder's logarithmic
+// tree encoding.
+const atMostOneLogThreshold = 12
+
+// Encoder allocates fresh Boolean variables and accumulates the clauses
+// emitted by the cardinality and pseudo-Boolean builders below, so callers
+// composing several constraints into one Problem (e.g. a sudoku-like puzzle)
+// don't have to track variable IDs by hand.
+type Encoder struct {
+	maxVar  int
+	clauses []Clause
+}
+
+// NewEncoder creates an Encoder whose fresh variables start after maxVar,
+// the highest variable number already used elsewhere in the Problem being
+// built.
+func NewEncoder(maxVar int) *Encoder {
+	return &Encoder{maxVar: maxVar}
+}
+
+// NewVar allocates and returns a fresh Literal not used anywhere else.
+func (e *Encoder) NewVar() Literal {
+	e.maxVar++
+	return Literal(e.maxVar)
+}
+
+// Clauses returns every clause emitted by the builder calls made on e so
+// far.
+func (e *Encoder) Clauses() []Clause {
+	return e.clauses
+}
+
+func (e *Encoder) addClauses(clauses ...Clause) {
+	e.clauses = append(e.clauses, clauses...)
+}
+
+// AtMost encodes "at most k of literals are true" and returns the emitted
+// clauses (also kept in e.Clauses()). For k == 1 over more than
+// atMostOneLogThreshold literals it uses the logarithmic tree encoding
+// (see atMostOneLog); otherwise it falls back to the pairwise/binomial
+// encoding used by the package-level AtMostOne, forbidding every (k+1)-sized
+// subset of literals from being true at once, which is only practical for
+// small k.
+func (e *Encoder) AtMost(k int, literals []Literal) []Clause {
+	n := len(literals)
+	if k < 0 {
+		k = 0
+	}
+	if k >= n {
+		return nil
+	}
+	if k == 0 {
+		var clauses []Clause
+		for _, l := range literals {
+			clauses = append(clauses, Clause{l.Negate()})
+		}
+		e.addClauses(clauses...)
+		return clauses
+	}
+	if k == 1 && n > atMostOneLogThreshold {
+		return e.atMostOneLog(literals)
+	}
+
+	var clauses []Clause
+	for _, subset := range combinations(literals, k+1) {
+		clause := make(Clause, len(subset))
+		for i, l := range subset {
+			clause[i] = l.Negate()
+		}
+		clauses = append(clauses, clause)
+	}
+	e.addClauses(clauses...)
+	return clauses
+}
+
+// AtLeast encodes "at least k of literals are true", via the standard
+// complement: at least k of n are true iff at most n-k are false.
+func (e *Encoder) AtLeast(k int, literals []Literal) []Clause {
+	n := len(literals)
+	if k <= 0 {
+		return nil
+	}
+	if k > n {
+		clauses := []Clause{{}}
+		e.addClauses(clauses...)
+		return clauses
+	}
+	negated := make([]Literal, n)
+	for i, l := range literals {
+		negated[i] = l.Negate()
+	}
+	return e.AtMost(n-k, negated)
+}
+
+// Exactly encodes "exactly k of literals are true" as the conjunction of
+// AtMost(k, literals) and AtLeast(k, literals).
+func (e *Encoder) Exactly(k int, literals []Literal) []Clause {
+	clauses := e.AtMost(k, literals)
+	clauses = append(clauses, e.AtLeast(k, literals)...)
+	return clauses
+}
+
+// PBLessEq encodes the pseudo-Boolean constraint
+// "sum(weights[i] * literals[i]) <= k" as a sequential weighted counter: for
+// each prefix length i and each threshold j in 1..k+1, a fresh register
+// variable register[i][j] means "the sum of weights of the first i literals
+// set true is at least j". Each register is fully defined both ways: forced
+// true whenever literal i alone reaches j, or literal i together with a
+// register[i-1] carry reaches it, or the count already reached j without
+// literal i (register[i][j] ⟸ ...), and forbidden from being true for any
+// other reason (register[i][j] ⟹ ...). That second direction matters beyond
+// plain SAT/UNSAT: without it, a single literal assignment admits many
+// equally-valid register assignments, and each one would be counted as a
+// distinct model by a caller enumerating every solution. Finally,
+// register[n][k+1] is forbidden, which blocks the sum from ever exceeding k.
+// This runs in O(len(literals)*k) clauses and variables, unlike expanding
+// each literal into weights[i] copies and delegating to AtMost's binomial
+// encoding, which blows up combinatorially for cage-sized killer sudoku
+// sums.
+func (e *Encoder) PBLessEq(weights []int, literals []Literal, k int) []Clause {
+	n := len(literals)
+	limit := k + 1
+	if limit <= 0 {
+		// k < 0: even the empty sum (0) already exceeds k, so no assignment
+		// can satisfy this constraint.
+		clauses := []Clause{{}}
+		e.addClauses(clauses...)
+		return clauses
+	}
+	if n == 0 {
+		return nil
+	}
+
+	register := make([][]Literal, n+1)
+	for i := 1; i <= n; i++ {
+		register[i] = make([]Literal, limit+1)
+		for j := 1; j <= limit; j++ {
+			register[i][j] = e.NewVar()
+		}
+	}
+
+	var clauses []Clause
+	for i := 1; i <= n; i++ {
+		w := weights[i-1]
+		lit := literals[i-1]
+		for j := 1; j <= limit; j++ {
+			switch {
+			case w >= j:
+				clauses = append(clauses, Clause{lit.Negate(), register[i][j]})
+			case i > 1:
+				clauses = append(clauses, Clause{lit.Negate(), register[i-1][j-w].Negate(), register[i][j]})
+			}
+			if i > 1 {
+				clauses = append(clauses, Clause{register[i-1][j].Negate(), register[i][j]})
+			}
+
+			switch {
+			case i == 1 && w >= j:
+				clauses = append(clauses, Clause{register[i][j].Negate(), lit})
+			case i == 1:
+				clauses = append(clauses, Clause{register[i][j].Negate()})
+			default:
+				clauses = append(clauses, Clause{register[i][j].Negate(), register[i-1][j], lit})
+				if w < j {
+					clauses = append(clauses, Clause{register[i][j].Negate(), register[i-1][j], register[i-1][j-w]})
+				}
+			}
+		}
+	}
+	clauses = append(clauses, Clause{register[n][limit].Negate()})
+
+	e.addClauses(clauses...)
+	return clauses
+}
+
+// PBGreaterEq encodes "sum(weights[i] * literals[i]) >= k" via the
+// complement of PBLessEq: that sum is at least k iff the same sum over the
+// negated literals is at most the weights' total minus k.
+func (e *Encoder) PBGreaterEq(weights []int, literals []Literal, k int) []Clause {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	negated := make([]Literal, len(literals))
+	for i, l := range literals {
+		negated[i] = l.Negate()
+	}
+	return e.PBLessEq(weights, negated, total-k)
+}
+
+// atMostOneLog encodes "at most one of literals is true" in O(n log n)
+// clauses instead of AtMostOne's O(n^2): it recursively splits literals in
+// half and allocates one auxiliary variable per internal node to record
+// which half the (at most one) true literal, if any, falls into — a literal
+// in the right half being true forces aux true, a literal in the left half
+// being true forces aux false, so at most one half can hold a true literal,
+// and the recursive calls keep each half itself down to at most one. aux is
+// also forced false when no right-half literal is true, so each assignment
+// of literals has exactly one matching assignment of the auxiliaries and
+// enumerating models does not repeat it. This is the log encoding of
+// at-most-one used by solvers such as Bule.
+func (e *Encoder) atMostOneLog(literals []Literal) []Clause {
+	if len(literals) <= 1 {
+		return nil
+	}
+	mid := len(literals) / 2
+	left, right := literals[:mid], literals[mid:]
+	aux := e.NewVar()
+
+	var clauses []Clause
+	for _, l := range right {
+		clauses = append(clauses, Clause{l.Negate(), aux})
+	}
+	for _, l := range left {
+		clauses = append(clauses, Clause{l.Negate(), aux.Negate()})
+	}
+	definition := make(Clause, 0, len(right)+1)
+	definition = append(definition, aux.Negate())
+	definition = append(definition, right...)
+	clauses = append(clauses, definition)
+	e.addClauses(clauses...)
+
+	clauses = append(clauses, e.atMostOneLog(left)...)
+	clauses = append(clauses, e.atMostOneLog(right)...)
+	return clauses
+}
+
+// combinations returns every r-sized subset of literals, in no particular
+// order.
+func combinations(literals []Literal, r int) [][]Literal {
+	if r <= 0 || r > len(literals) {
+		return nil
+	}
+	var result [][]Literal
+	var pick func(start int, current []Literal)
+	pick = func(start int, current []Literal) {
+		if len(current) == r {
+			subset := make([]Literal, r)
+			copy(subset, current)
+			result = append(result, subset)
+			return
+		}
+		for i := start; i < len(literals); i++ {
+			next := make([]Literal, len(current), len(current)+1)
+			copy(next, current)
+			pick(i+1, append(next, literals[i]))
+		}
+	}
+	pick(0, nil)
+	return result
+}
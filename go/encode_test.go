@@ -0,0 +1,152 @@
+package sss
+
+import "testing"
+
+func TestEncoder_AtMost(t *testing.T) {
+	enc := NewEncoder(3)
+	clauses := enc.AtMost(1, []Literal{1, 2, 3})
+	problem := Problem(clauses)
+
+	for _, solution := range SolveAll(NewDefaultSolver().Solve(problem)) {
+		if countTrue(solution, 1, 2, 3) > 1 {
+			t.Errorf("solution %v sets more than 1 of {1,2,3}, want at most 1", solution)
+		}
+	}
+}
+
+func TestEncoder_AtLeast(t *testing.T) {
+	enc := NewEncoder(3)
+	clauses := enc.AtLeast(2, []Literal{1, 2, 3})
+	problem := Problem(clauses)
+
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) == 0 {
+		t.Fatal("Solve() found no solutions, want at least one")
+	}
+	for _, solution := range solutions {
+		if countTrue(solution, 1, 2, 3) < 2 {
+			t.Errorf("solution %v sets fewer than 2 of {1,2,3}, want at least 2", solution)
+		}
+	}
+}
+
+func TestEncoder_Exactly(t *testing.T) {
+	enc := NewEncoder(3)
+	clauses := enc.Exactly(2, []Literal{1, 2, 3})
+	problem := Problem(clauses)
+
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) != 3 {
+		t.Errorf("len(Solve()) = %d, want 3 (C(3,2))", len(solutions))
+	}
+	for _, solution := range solutions {
+		if got := countTrue(solution, 1, 2, 3); got != 2 {
+			t.Errorf("solution %v sets %d of {1,2,3}, want exactly 2", solution, got)
+		}
+	}
+}
+
+func TestEncoder_PBLessEq(t *testing.T) {
+	// weights 1, 2, 3 on literals 1, 2, 3; sum <= 3 rules out {1,3}, {2,3} and {1,2,3}.
+	enc := NewEncoder(3)
+	clauses := enc.PBLessEq([]int{1, 2, 3}, []Literal{1, 2, 3}, 3)
+	problem := Problem(clauses)
+
+	for _, solution := range SolveAll(NewDefaultSolver().Solve(problem)) {
+		weight := 0
+		set := setOf(solution)
+		for i, w := range []int{1, 2, 3} {
+			if set[Literal(i+1)] {
+				weight += w
+			}
+		}
+		if weight > 3 {
+			t.Errorf("solution %v has weight %d, want at most 3", solution, weight)
+		}
+	}
+}
+
+func TestEncoder_PBLessEq_staysSmallForLargeWeights(t *testing.T) {
+	// A weight-10 literal on a 2-literal sum bounded by 10 used to expand to
+	// 20 unary copies and call combinations(20, 11); the sequential counter
+	// must instead stay polynomial in len(literals)*k.
+	enc := NewEncoder(2)
+	clauses := enc.PBLessEq([]int{10, 10}, []Literal{1, 2}, 10)
+	problem := Problem(clauses)
+
+	for _, solution := range SolveAll(NewDefaultSolver().Solve(problem)) {
+		if countTrue(solution, 1, 2) > 1 {
+			t.Errorf("solution %v sets both weight-10 literals, want at most 1", solution)
+		}
+	}
+}
+
+func TestEncoder_PBGreaterEq(t *testing.T) {
+	enc := NewEncoder(3)
+	// weights 2, 3, 4 on literals 1, 2, 3; sum >= 5 rules out {}, {1}, {2}, {3}.
+	clauses := enc.PBGreaterEq([]int{2, 3, 4}, []Literal{1, 2, 3}, 5)
+	problem := Problem(clauses)
+
+	solutions := SolveAll(NewDefaultSolver().Solve(problem))
+	if len(solutions) == 0 {
+		t.Fatal("Solve() found no solutions, want at least one")
+	}
+	weightOf := []int{2, 3, 4}
+	for _, solution := range solutions {
+		weight := 0
+		set := setOf(solution)
+		for i, w := range weightOf {
+			if set[Literal(i+1)] {
+				weight += w
+			}
+		}
+		if weight < 5 {
+			t.Errorf("solution %v has weight %d, want at least 5", solution, weight)
+		}
+	}
+}
+
+func TestEncoder_atMostOneLog(t *testing.T) {
+	literals := make([]Literal, atMostOneLogThreshold+1)
+	for i := range literals {
+		literals[i] = Literal(i + 1)
+	}
+	enc := NewEncoder(len(literals))
+	clauses := enc.AtMost(1, literals)
+	problem := Problem(clauses)
+
+	for _, solution := range SolveAll(NewDefaultSolver().Solve(problem)) {
+		if countTrue(solution, literals...) > 1 {
+			t.Errorf("solution %v sets more than 1 literal, want at most 1", solution)
+		}
+	}
+}
+
+func TestEncoder_NewVar(t *testing.T) {
+	enc := NewEncoder(5)
+	if got := enc.NewVar(); got != 6 {
+		t.Errorf("NewVar() = %v, want 6", got)
+	}
+	if got := enc.NewVar(); got != 7 {
+		t.Errorf("NewVar() = %v, want 7", got)
+	}
+}
+
+func setOf(assignment Assignment) map[Literal]bool {
+	set := make(map[Literal]bool, len(assignment))
+	for _, l := range assignment {
+		set[l] = true
+	}
+	return set
+}
+
+func countTrue(assignment Assignment, literals ...Literal) int {
+	set := setOf(assignment)
+	count := 0
+	for _, l := range literals {
+		if set[l] {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,266 @@
+package sss
+
+// Puzzle is a sudoku-family puzzle: a size x size grid of cells, each taking
+// a value in 1..size, constrained by an arbitrary list of Constraints. It
+// generalizes Sudoku (fixed at 9x9 with hard-coded row/column/box rules) to
+// other sizes and rule sets — 4x4 and 16x16 grids, jigsaw regions, killer
+// cages, X-sudoku diagonals — while still solving through the same Solver
+// implementations via CNF encoding.
+type Puzzle struct {
+	size    int
+	problem Problem
+}
+
+// Constraint encodes one rule of a Puzzle to CNF, appending its clauses to
+// enc and returning them. Implementations use cellVar(size, cell, value) to
+// refer to the Boolean variable meaning "cell holds value".
+type Constraint interface {
+	Encode(size int, enc *Encoder) []Clause
+}
+
+// cellVar returns the Boolean variable meaning "cell (numbered
+// row*size+col) holds value (in 1..size)".
+func cellVar(size, cell, value int) Literal {
+	return Literal(cell*size + value)
+}
+
+// NewPuzzle builds a Puzzle of the given size, whose cells must each take
+// exactly one value (enforced automatically) subject to the given
+// constraints.
+func NewPuzzle(size int, constraints []Constraint) *Puzzle {
+	enc := NewEncoder(size * size * size)
+
+	var clauses []Clause
+	for cell := 0; cell < size*size; cell++ {
+		literals := make([]Literal, size)
+		for value := 1; value <= size; value++ {
+			literals[value-1] = cellVar(size, cell, value)
+		}
+		clauses = append(clauses, ExactlyOne(literals)...)
+	}
+	for _, constraint := range constraints {
+		clauses = append(clauses, constraint.Encode(size, enc)...)
+	}
+
+	return &Puzzle{size: size, problem: Problem(clauses)}
+}
+
+// UniqueConstraint requires every listed cell (numbered row*size+col) to
+// take a distinct value, e.g. the cells of a sudoku row, column, box, jigsaw
+// region, or diagonal.
+type UniqueConstraint []int
+
+func (u UniqueConstraint) Encode(size int, enc *Encoder) []Clause {
+	return encodeUnique(size, enc, u)
+}
+
+func encodeUnique(size int, enc *Encoder, cells []int) []Clause {
+	var clauses []Clause
+	for value := 1; value <= size; value++ {
+		literals := make([]Literal, len(cells))
+		for i, cell := range cells {
+			literals[i] = cellVar(size, cell, value)
+		}
+		clauses = append(clauses, enc.AtMost(1, literals)...)
+	}
+	return clauses
+}
+
+// GivenConstraint fixes a single cell (numbered row*size+col) to Value, the
+// way a clue fixes a cell in a sudoku grid.
+type GivenConstraint struct {
+	Cell  int
+	Value int
+}
+
+func (g GivenConstraint) Encode(size int, enc *Encoder) []Clause {
+	return []Clause{{cellVar(size, g.Cell, g.Value)}}
+}
+
+// SumConstraint requires the listed Cells to take distinct values summing to
+// Total, the rule of a killer sudoku cage.
+type SumConstraint struct {
+	Cells []int
+	Total int
+}
+
+func (s SumConstraint) Encode(size int, enc *Encoder) []Clause {
+	clauses := encodeUnique(size, enc, s.Cells)
+
+	var weights []int
+	var literals []Literal
+	for _, cell := range s.Cells {
+		for value := 1; value <= size; value++ {
+			weights = append(weights, value)
+			literals = append(literals, cellVar(size, cell, value))
+		}
+	}
+	clauses = append(clauses, enc.PBLessEq(weights, literals, s.Total)...)
+	clauses = append(clauses, enc.PBGreaterEq(weights, literals, s.Total)...)
+	return clauses
+}
+
+// Solution returns a grid satisfying p, found with NewDefaultSolver, or false
+// if p has none. Unlike Solutions, this stops the search after the first
+// model, so it is safe to call even on an underconstrained puzzle whose
+// solution space is too large to enumerate.
+func (p *Puzzle) Solution() (SudokuGrid, bool) {
+	return p.SolutionUsing(NewDefaultSolver())
+}
+
+// SolutionUsing returns a grid satisfying p, found with solver, or false if p
+// has none. See Solution.
+func (p *Puzzle) SolutionUsing(solver Solver) (SudokuGrid, bool) {
+	for assignment := range solver.Solve(p.problem) {
+		return p.gridFrom(assignment), true
+	}
+	return nil, false
+}
+
+// Solutions returns every grid satisfying p, found with NewDefaultSolver. It
+// drains the solver's iterator to completion, so it is only safe on a puzzle
+// whose solution space is small and bounded (e.g. a sudoku with enough clues
+// to pin a handful of solutions) — for anything else, prefer Solution, which
+// stops after the first model.
+func (p *Puzzle) Solutions() []SudokuGrid {
+	return p.SolutionsUsing(NewDefaultSolver())
+}
+
+// SolutionsUsing returns every grid satisfying p, found with solver. See the
+// caveat on Solutions.
+func (p *Puzzle) SolutionsUsing(solver Solver) []SudokuGrid {
+	var solutions []SudokuGrid
+	for assignment := range solver.Solve(p.problem) {
+		solutions = append(solutions, p.gridFrom(assignment))
+	}
+	return solutions
+}
+
+func (p *Puzzle) gridFrom(assignment Assignment) SudokuGrid {
+	grid := make(SudokuGrid, p.size)
+	for i := range grid {
+		grid[i] = make([]int, p.size)
+	}
+	nbCellVars := p.size * p.size * p.size
+	for _, literal := range assignment {
+		if literal < 1 || int(literal) > nbCellVars {
+			// Auxiliary variables introduced by Encoder's logarithmic
+			// at-most-one encoding (see atMostOneLog) live beyond
+			// nbCellVars and don't correspond to a (cell, value) pair.
+			continue
+		}
+		lit := int(literal) - 1
+		value := lit%p.size + 1
+		cell := lit / p.size
+		grid[cell/p.size][cell%p.size] = value
+	}
+	return grid
+}
+
+// rowColConstraints returns the UniqueConstraints requiring every row and
+// every column of a size x size grid to hold distinct values.
+func rowColConstraints(size int) []Constraint {
+	var constraints []Constraint
+	for row := 0; row < size; row++ {
+		cells := make([]int, size)
+		for col := 0; col < size; col++ {
+			cells[col] = row*size + col
+		}
+		constraints = append(constraints, UniqueConstraint(cells))
+	}
+	for col := 0; col < size; col++ {
+		cells := make([]int, size)
+		for row := 0; row < size; row++ {
+			cells[row] = row*size + col
+		}
+		constraints = append(constraints, UniqueConstraint(cells))
+	}
+	return constraints
+}
+
+// givenConstraints returns one GivenConstraint per non-zero cell of givens.
+func givenConstraints(size int, givens [][]int) []Constraint {
+	var constraints []Constraint
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			if value := givens[row][col]; value > 0 {
+				constraints = append(constraints, GivenConstraint{Cell: row*size + col, Value: value})
+			}
+		}
+	}
+	return constraints
+}
+
+// boxSize returns the side length of size's square boxes (3 for size 9, 2
+// for size 4, 4 for size 16), or 0 if size is not a perfect square and so
+// has no standard box layout (use NewJigsawPuzzle instead).
+func boxSize(size int) int {
+	for b := 1; b*b <= size; b++ {
+		if b*b == size {
+			return b
+		}
+	}
+	return 0
+}
+
+// boxConstraints returns the UniqueConstraints requiring every boxSize x
+// boxSize box of a size x size grid to hold distinct values.
+func boxConstraints(size int) []Constraint {
+	b := boxSize(size)
+	var constraints []Constraint
+	for boxRow := 0; boxRow < b; boxRow++ {
+		for boxCol := 0; boxCol < b; boxCol++ {
+			cells := make([]int, 0, size)
+			for r := 0; r < b; r++ {
+				for c := 0; c < b; c++ {
+					row, col := boxRow*b+r, boxCol*b+c
+					cells = append(cells, row*size+col)
+				}
+			}
+			constraints = append(constraints, UniqueConstraint(cells))
+		}
+	}
+	return constraints
+}
+
+// NewStandardPuzzle builds a classic sudoku of the given size (4, 9, 16, ...
+// any perfect square, so its boxSize x boxSize boxes tile evenly) with row,
+// column and box uniqueness constraints, plus one GivenConstraint per
+// non-zero cell of givens. For a size with no square box layout (e.g. 6),
+// use NewJigsawPuzzle with an explicit region partition instead.
+func NewStandardPuzzle(size int, givens [][]int) *Puzzle {
+	constraints := rowColConstraints(size)
+	constraints = append(constraints, boxConstraints(size)...)
+	constraints = append(constraints, givenConstraints(size, givens)...)
+	return NewPuzzle(size, constraints)
+}
+
+// NewJigsawPuzzle builds a jigsaw sudoku: row and column uniqueness as
+// usual, but regions replacing the standard 3x3 boxes with an arbitrary
+// partition of the grid's cells into len(regions) same-sized groups.
+func NewJigsawPuzzle(size int, regions [][]int, givens [][]int) *Puzzle {
+	constraints := rowColConstraints(size)
+	for _, region := range regions {
+		constraints = append(constraints, UniqueConstraint(region))
+	}
+	constraints = append(constraints, givenConstraints(size, givens)...)
+	return NewPuzzle(size, constraints)
+}
+
+// NewXPuzzle builds an X-sudoku: a standard sudoku of the given size, plus
+// uniqueness constraints on its two main diagonals.
+func NewXPuzzle(size int, givens [][]int) *Puzzle {
+	constraints := rowColConstraints(size)
+	constraints = append(constraints, boxConstraints(size)...)
+
+	diagonal1 := make([]int, size)
+	diagonal2 := make([]int, size)
+	for i := 0; i < size; i++ {
+		diagonal1[i] = i*size + i
+		diagonal2[i] = i*size + (size - 1 - i)
+	}
+	constraints = append(constraints, UniqueConstraint(diagonal1), UniqueConstraint(diagonal2))
+
+	constraints = append(constraints, givenConstraints(size, givens)...)
+	return NewPuzzle(size, constraints)
+}
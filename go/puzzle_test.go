@@ -0,0 +1,167 @@
+package sss
+
+import "testing"
+
+func TestNewStandardPuzzle_4x4(t *testing.T) {
+	givens := [][]int{
+		{1, 0, 0, 0},
+		{0, 0, 1, 0},
+		{0, 1, 0, 0},
+		{0, 0, 0, 1},
+	}
+	puzzle := NewStandardPuzzle(4, givens)
+	solutions := puzzle.Solutions()
+	if len(solutions) == 0 {
+		t.Fatal("Solutions() found none, want at least one")
+	}
+	for _, grid := range solutions {
+		checkRowsColsBoxesDistinct(t, grid, 4, 2)
+		checkGivensHonored(t, grid, givens)
+	}
+}
+
+func TestNewStandardPuzzle_16x16(t *testing.T) {
+	// 16 cells per row/column crosses atMostOneLogThreshold, exercising the
+	// log at-most-one encoding's auxiliary variables end to end.
+	givens := make([][]int, 16)
+	for row := range givens {
+		givens[row] = make([]int, 16)
+	}
+	givens[0][0] = 1
+	puzzle := NewStandardPuzzle(16, givens)
+	// A single clue leaves an astronomically large solution space, so pull
+	// just one via Solution instead of Solutions, which would never finish
+	// draining every model.
+	grid, ok := puzzle.Solution()
+	if !ok {
+		t.Fatal("Solution() found none, want one")
+	}
+	checkRowsColsBoxesDistinct(t, grid, 16, 4)
+	if grid[0][0] != 1 {
+		t.Errorf("grid[0][0] = %d, want 1", grid[0][0])
+	}
+}
+
+func TestNewJigsawPuzzle_4x4(t *testing.T) {
+	// Four regions passed explicitly (here the same cells as the 2x2 boxes)
+	// instead of the boxes NewStandardPuzzle derives.
+	regions := [][]int{
+		{0, 1, 4, 5},
+		{2, 3, 6, 7},
+		{8, 9, 12, 13},
+		{10, 11, 14, 15},
+	}
+	givens := [][]int{
+		{1, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 2},
+	}
+	puzzle := NewJigsawPuzzle(4, regions, givens)
+	solutions := puzzle.Solutions()
+	if len(solutions) == 0 {
+		t.Fatal("Solutions() found none, want at least one")
+	}
+	for _, grid := range solutions {
+		checkDistinct(t, cellsOf(grid, []int{0, 1, 4, 5}))
+		checkGivensHonored(t, grid, givens)
+	}
+}
+
+func TestNewXPuzzle_4x4(t *testing.T) {
+	puzzle := NewXPuzzle(4, [][]int{
+		{1, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+	})
+	solutions := puzzle.Solutions()
+	if len(solutions) == 0 {
+		t.Fatal("Solutions() found none, want at least one")
+	}
+	for _, grid := range solutions {
+		checkRowsColsBoxesDistinct(t, grid, 4, 2)
+		diagonal := []int{grid[0][0], grid[1][1], grid[2][2], grid[3][3]}
+		checkDistinct(t, diagonal)
+	}
+}
+
+func TestSumConstraint(t *testing.T) {
+	// A 4x4 grid with one killer cage over the top-left 2 cells summing to 3:
+	// the only way to place distinct values from {1,2,3,4} summing to 3 is
+	// {1,2} in some order.
+	constraints := rowColConstraints(4)
+	constraints = append(constraints, boxConstraints(4)...)
+	constraints = append(constraints, SumConstraint{Cells: []int{0, 1}, Total: 3})
+	puzzle := NewPuzzle(4, constraints)
+
+	solutions := puzzle.Solutions()
+	if len(solutions) == 0 {
+		t.Fatal("Solutions() found none, want at least one")
+	}
+	for _, grid := range solutions {
+		sum := grid[0][0] + grid[0][1]
+		if sum != 3 {
+			t.Errorf("cage sum = %d, want 3", sum)
+		}
+		if grid[0][0] == grid[0][1] {
+			t.Errorf("cage cells = %v, want distinct values", []int{grid[0][0], grid[0][1]})
+		}
+	}
+}
+
+func checkRowsColsBoxesDistinct(t *testing.T, grid SudokuGrid, size, box int) {
+	t.Helper()
+	for row := 0; row < size; row++ {
+		checkDistinct(t, grid[row])
+	}
+	for col := 0; col < size; col++ {
+		column := make([]int, size)
+		for row := 0; row < size; row++ {
+			column[row] = grid[row][col]
+		}
+		checkDistinct(t, column)
+	}
+	for boxRow := 0; boxRow < size; boxRow += box {
+		for boxCol := 0; boxCol < size; boxCol += box {
+			var values []int
+			for r := 0; r < box; r++ {
+				for c := 0; c < box; c++ {
+					values = append(values, grid[boxRow+r][boxCol+c])
+				}
+			}
+			checkDistinct(t, values)
+		}
+	}
+}
+
+func checkGivensHonored(t *testing.T, grid SudokuGrid, givens [][]int) {
+	t.Helper()
+	for row := range givens {
+		for col := range givens[row] {
+			if want := givens[row][col]; want > 0 && grid[row][col] != want {
+				t.Errorf("grid[%d][%d] = %d, want given %d", row, col, grid[row][col], want)
+			}
+		}
+	}
+}
+
+func checkDistinct(t *testing.T, values []int) {
+	t.Helper()
+	seen := make(map[int]bool, len(values))
+	for _, v := range values {
+		if seen[v] {
+			t.Errorf("values %v contain duplicate %d", values, v)
+		}
+		seen[v] = true
+	}
+}
+
+func cellsOf(grid SudokuGrid, cells []int) []int {
+	size := len(grid)
+	values := make([]int, len(cells))
+	for i, cell := range cells {
+		values[i] = grid[cell/size][cell%size]
+	}
+	return values
+}
@@ -1,10 +1,14 @@
-package main
+// Package sss provides a small SAT solving toolkit: clause/problem types, a
+// CDCL solver, cardinality/pseudo-Boolean constraint encoders, and a
+// sudoku-family puzzle solver built on top of them.
+package sss
 
 import (
 	"fmt"
-	"slices"
+	"iter"
 	"strings"
-	"time"
+
+	"github.com/super7ramp/sss/dlx"
 )
 
 // Literal represents a propositional logic literal.
@@ -17,104 +21,70 @@ func (l Literal) Negate() Literal {
 // Clause represents a disjunction ("or") of literals.
 type Clause []Literal
 
-func (c Clause) IsEmpty() bool {
-	return len(c) == 0
-}
-
-func (c Clause) Head() Literal {
-	return c[0]
-}
-
-func (c Clause) Without(literal Literal) Clause {
-	var newClause Clause
-	for _, currentLiteral := range c {
-		if currentLiteral != literal {
-			newClause = append(newClause, currentLiteral)
-		}
-	}
-	return newClause
-}
-
 // Problem represents a conjunction ("and") of clauses.
 type Problem []Clause
 
-func (p Problem) IsEmpty() bool {
-	return len(p) == 0
-}
-
-func (p Problem) Head() Clause {
-	return p[0]
-}
-
 // Assignment represents a set of literals satisfying a Problem.
 type Assignment []Literal
 
-// Propagator is a function that propagates a unit clause (a literal) to simplify the problem.
-type Propagator func(literal Literal, problem Problem) Problem
-
-// DefaultPropagator is the default Propagator implementation.
-func DefaultPropagator(literal Literal, problem Problem) Problem {
-	negatedLiteral := literal.Negate()
-	var clausesAfterPropagation Problem
-	for _, clause := range problem {
-		if slices.Contains(clause, literal) {
-			continue
-		}
-		updatedClause := clause.Without(negatedLiteral)
-		if updatedClause.IsEmpty() {
-			return []Clause{[]Literal{}}
-		}
-		clausesAfterPropagation = append(clausesAfterPropagation, updatedClause)
-	}
-	slices.SortFunc(clausesAfterPropagation, compareByClauseLength)
-	return clausesAfterPropagation
-}
-
-func compareByClauseLength(a, b Clause) int {
-	return len(a) - len(b)
-}
-
 // Solver defines the interface for a SAT solver.
 type Solver interface {
-	// TODO return an iterator
-	Solve(p Problem) []Assignment
-}
-
+	// Solve returns an iterator over every Assignment satisfying p, found
+	// lazily as the search progresses. Ranging over it stops the search as
+	// soon as the loop body stops pulling values (e.g. after a first model,
+	// or via a context check), which matters for problems with many models
+	// or none at all. Use SolveAll to collect every model into a slice.
+	Solve(p Problem) iter.Seq[Assignment]
+}
+
+// SolveAll drains seq into a slice, for callers that want every model of a
+// Problem rather than stopping early.
+func SolveAll(seq iter.Seq[Assignment]) []Assignment {
+	var assignments []Assignment
+	for assignment := range seq {
+		assignments = append(assignments, assignment)
+	}
+	return assignments
+}
+
+// DefaultSolver is a Conflict-Driven Clause Learning (CDCL) Solver: it
+// propagates unit clauses through a two-watched-literals scheme, learns a
+// clause from every conflict via first-UIP resolution over the implication
+// graph, and backjumps non-chronologically to the learned clause's second
+// highest decision level. See NewDefaultSolverWithOptions to tune its
+// restart policy and learned-clause database size.
 type DefaultSolver struct {
-	propagate Propagator
+	options Options
 }
 
+// NewDefaultSolver creates a DefaultSolver with DefaultOptions.
 func NewDefaultSolver() *DefaultSolver {
-	return &DefaultSolver{propagate: DefaultPropagator}
+	return NewDefaultSolverWithOptions(DefaultOptions())
 }
 
-func (s *DefaultSolver) Solve(problem Problem) []Assignment {
-
-	if problem.IsEmpty() {
-		return []Assignment{{}}
-	}
-
-	headClause := problem.Head()
-	if headClause.IsEmpty() {
-		return []Assignment{}
-	}
-
-	literal := headClause.Head()
-
-	literalPropagated := s.propagate(literal, problem)
-	assignmentsWithLiteral := s.Solve(literalPropagated)
-	for i, assignment := range assignmentsWithLiteral {
-		assignmentsWithLiteral[i] = slices.Insert(assignment, 0, literal)
-	}
+// NewDefaultSolverWithOptions creates a DefaultSolver tuned with the given Options.
+func NewDefaultSolverWithOptions(options Options) *DefaultSolver {
+	return &DefaultSolver{options: options}
+}
 
-	negatedLiteral := literal.Negate()
-	negatedLiteralPropagated := s.propagate(negatedLiteral, problem)
-	assignmentsWithLiteralNegated := s.Solve(negatedLiteralPropagated)
-	for i, assignment := range assignmentsWithLiteralNegated {
-		assignmentsWithLiteralNegated[i] = slices.Insert(assignment, 0, negatedLiteral)
+// Solve returns an iterator over every Assignment satisfying problem, found
+// by repeatedly running CDCL search and blocking each yielded model (adding
+// its negation as a permanent clause) so the next search explores the
+// remaining models. The search stops as soon as the caller stops ranging.
+func (s *DefaultSolver) Solve(problem Problem) iter.Seq[Assignment] {
+	return func(yield func(Assignment) bool) {
+		engine := newCDCLEngine(problem, s.options)
+		for {
+			model, ok := engine.solve()
+			if !ok {
+				return
+			}
+			if !yield(model) {
+				return
+			}
+			engine.blockModel(model)
+		}
 	}
-
-	return slices.Concat(assignmentsWithLiteral, assignmentsWithLiteralNegated)
 }
 
 func AtMostOne(literals []Literal) []Clause {
@@ -140,147 +110,92 @@ func (sg SudokuGrid) String() string {
 	return strings.ReplaceAll(formattedArray, "] ", "]\n")
 }
 
+// Sudoku is a classic 9x9 sudoku, solved either through the CNF/Solver path
+// (via its Puzzle) or, much faster, through SolveWithDLX.
 type Sudoku struct {
-	grid    SudokuGrid
-	problem Problem
+	grid   SudokuGrid
+	puzzle *Puzzle
 }
 
+// NewSudoku builds a 9x9 sudoku from the given grid of clues (0 for an empty
+// cell), as a Puzzle with the standard row/column/box rules.
 func NewSudoku(grid SudokuGrid) *Sudoku {
-	var clauses []Clause
-
-	// 1. No row contains dupe
-	for row := range 9 {
-		for value := 1; value <= 9; value++ {
-			literals := make([]Literal, 9)
-			for col := range 9 {
-				variable := sudokuVarNumber(row, col, value)
-				literals[col] = Literal(variable)
-			}
-			clauses = append(clauses, ExactlyOne(literals)...)
-		}
-	}
-
-	// 2. No column contains dupe
-	for col := range 9 {
-		for value := 1; value <= 9; value++ {
-			literals := make([]Literal, 9)
-			for row := range 9 {
-				variable := sudokuVarNumber(row, col, value)
-				literals[row] = Literal(variable)
-			}
-			clauses = append(clauses, ExactlyOne(literals)...)
-		}
-	}
-
-	// 3. No 3x3 box contains dupe
-	for startRow := 0; startRow < 9; startRow += 3 {
-		for startCol := 0; startCol < 9; startCol += 3 {
-			for value := 1; value <= 9; value++ {
-				literals := make([]Literal, 9)
-				for rowOffset := range 3 {
-					for colOffset := range 3 {
-						variable := sudokuVarNumber(startRow+rowOffset, startCol+colOffset, value)
-						literals[rowOffset*3+colOffset] = Literal(variable)
-					}
-				}
-				clauses = append(clauses, ExactlyOne(literals)...)
-			}
-		}
-	}
-
-	// 4. No cell contains dupe
-	for row := range 9 {
-		for col := range 9 {
-			literals := make([]Literal, 9)
-			for value := 1; value <= 9; value++ {
-				variable := sudokuVarNumber(row, col, value)
-				literals[value-1] = Literal(variable)
-			}
-			clauses = append(clauses, ExactlyOne(literals)...)
-		}
-	}
-
-	// 5. Initial values
-	for row := range 9 {
-		for col := range 9 {
-			value := grid[row][col]
-			if value > 0 {
-				variable := sudokuVarNumber(row, col, value)
-				clauses = append(clauses, Clause{Literal(variable)})
-			}
-		}
-	}
+	return &Sudoku{grid: grid, puzzle: NewStandardPuzzle(9, grid)}
+}
 
-	problem := Problem(clauses)
-	return &Sudoku{grid: grid, problem: problem}
+// Solution returns a grid satisfying s, found with NewDefaultSolver, or false
+// if s has none. It stops the search after the first model; see the caveat
+// on Solutions.
+func (s *Sudoku) Solution() (SudokuGrid, bool) {
+	return s.puzzle.Solution()
 }
 
-func sudokuVarNumber(row, col, value int) int {
-	return row*9*9 + col*9 + value
+// SolutionUsing returns a grid satisfying s, found with solver, or false if s
+// has none.
+func (s *Sudoku) SolutionUsing(solver Solver) (SudokuGrid, bool) {
+	return s.puzzle.SolutionUsing(solver)
 }
 
+// Solutions returns every grid satisfying s, found with NewDefaultSolver. It
+// drains the solver's iterator to completion, so it is only safe to call on
+// a sudoku with enough clues to pin a handful of solutions — prefer Solution
+// for the common case of wanting just one.
 func (s *Sudoku) Solutions() []SudokuGrid {
-	return s.SolutionsUsing(NewDefaultSolver())
+	return s.puzzle.Solutions()
 }
 
+// SolutionsUsing returns every grid satisfying s, found with solver. See the
+// caveat on Solutions.
 func (s *Sudoku) SolutionsUsing(solver Solver) []SudokuGrid {
-	var solutions []SudokuGrid
-	for _, assignment := range solver.Solve(s.problem) {
-		solutions = append(solutions, s.gridFrom(assignment))
+	return s.puzzle.SolutionsUsing(solver)
+}
+
+// SolveWithDLX returns every solution to s, found with Knuth's Algorithm X
+// over a Dancing Links matrix instead of the CNF/DefaultSolver path: each of
+// the 9*9*9 (row, column, value) options covers the four standard sudoku
+// constraints (cell filled, row/value, column/value, box/value), and a
+// given clue restricts a cell to the single option matching it. This is
+// dramatically faster than the CNF encoding behind Solutions for plain
+// sudokus.
+func (s *Sudoku) SolveWithDLX() []SudokuGrid {
+	const size = 9
+	ec := dlx.NewExactCover(4 * size * size)
+	optionCell := make(map[int][3]int, size*size*size)
+	for row := range size {
+		for col := range size {
+			given := s.grid[row][col]
+			for value := 1; value <= size; value++ {
+				if given > 0 && value != given {
+					continue
+				}
+				box := (row/3)*3 + col/3
+				columns := []int{
+					row*size + col,
+					size*size + row*size + (value - 1),
+					2*size*size + col*size + (value - 1),
+					3*size*size + box*size + (value - 1),
+				}
+				rowID := ec.AddOption(columns)
+				optionCell[rowID] = [3]int{row, col, value}
+			}
+		}
 	}
-	return solutions
-}
 
-func (s *Sudoku) gridFrom(assignment Assignment) [][]int {
-	grid := make([][]int, 9)
-	for i := range grid {
-		grid[i] = make([]int, 9)
-	}
-	for _, literal := range assignment {
-		if literal < 1 {
-			continue
+	var solutions []SudokuGrid
+	for _, options := range dlx.NewSolver().Solve(ec) {
+		grid := make(SudokuGrid, size)
+		for i := range grid {
+			grid[i] = make([]int, size)
+		}
+		for _, rowID := range options {
+			cell := optionCell[rowID]
+			grid[cell[0]][cell[1]] = cell[2]
 		}
-		value := ((literal - 1) % 9) + 1
-		col := ((literal - 1) / 9) % 9
-		row := (literal - 1) / (9 * 9)
-		grid[row][col] = int(value)
+		solutions = append(solutions, grid)
 	}
-	return grid
+	return solutions
 }
 
 func (s *Sudoku) String() string {
 	return fmt.Sprint(s.grid)
 }
-
-func main() {
-	fmt.Println("Example: Trivial clauses")
-	fmt.Println("Input: (1 or 2) and (-2 or 3)")
-	problem := Problem{{1, 2}, {-2, 3}}
-	solver := NewDefaultSolver()
-	fmt.Println("Solutions:")
-	for _, assignment := range solver.Solve(problem) {
-		fmt.Println(assignment)
-	}
-	fmt.Println()
-
-	fmt.Println("Example: A sudoku problem")
-	fmt.Println("Input:")
-	sudoku := NewSudoku([][]int{
-		{0, 2, 6, 0, 0, 0, 8, 1, 0},
-		{3, 0, 0, 7, 0, 8, 0, 0, 6},
-		{4, 0, 0, 0, 5, 0, 0, 0, 7},
-		{0, 5, 0, 1, 0, 7, 0, 9, 0},
-		{0, 0, 3, 9, 0, 5, 1, 0, 0},
-		{0, 4, 0, 3, 0, 2, 0, 5, 0},
-		{1, 0, 0, 0, 3, 0, 0, 0, 2},
-		{5, 0, 0, 2, 0, 4, 0, 0, 9},
-		{0, 3, 8, 0, 0, 0, 4, 6, 0},
-	})
-	fmt.Println(sudoku)
-	fmt.Println("Solutions:")
-	for _, solution := range sudoku.Solutions() {
-		start := time.Now()
-		fmt.Println(solution)
-		fmt.Println("Time: ", time.Since(start))
-	}
-}
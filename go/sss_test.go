@@ -0,0 +1,41 @@
+package sss
+
+import "testing"
+
+func TestSudoku_solvesThroughPuzzle(t *testing.T) {
+	// A grid with enough clues to pin the solution, covering rows, columns
+	// and boxes.
+	grid := SudokuGrid{
+		{5, 3, 0, 0, 7, 0, 0, 0, 0},
+		{6, 0, 0, 1, 9, 5, 0, 0, 0},
+		{0, 9, 8, 0, 0, 0, 0, 6, 0},
+		{8, 0, 0, 0, 6, 0, 0, 0, 3},
+		{4, 0, 0, 8, 0, 3, 0, 0, 1},
+		{7, 0, 0, 0, 2, 0, 0, 0, 6},
+		{0, 6, 0, 0, 0, 0, 2, 8, 0},
+		{0, 0, 0, 4, 1, 9, 0, 0, 5},
+		{0, 0, 0, 0, 8, 0, 0, 7, 9},
+	}
+	sudoku := NewSudoku(grid)
+
+	solutions := sudoku.Solutions()
+	if len(solutions) == 0 {
+		t.Fatal("Solutions() found none, want at least one")
+	}
+	for _, solved := range solutions {
+		checkRowsColsBoxesDistinct(t, solved, 9, 3)
+		checkGivensHonored(t, solved, grid)
+	}
+
+	dlxSolutions := sudoku.SolveWithDLX()
+	if len(dlxSolutions) != len(solutions) {
+		t.Errorf("len(SolveWithDLX()) = %d, want %d (same as Solutions())", len(dlxSolutions), len(solutions))
+	}
+
+	solved, ok := sudoku.Solution()
+	if !ok {
+		t.Fatal("Solution() found none, want one")
+	}
+	checkRowsColsBoxesDistinct(t, solved, 9, 3)
+	checkGivensHonored(t, solved, grid)
+}